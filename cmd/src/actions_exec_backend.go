@@ -4,8 +4,6 @@ import (
 	"context"
 	"sync"
 	"time"
-
-	"github.com/neelance/parallel"
 )
 
 type actionExecutorOptions struct {
@@ -15,6 +13,44 @@ type actionExecutorOptions struct {
 	clearCache bool
 	cache      actionExecutionCache
 
+	// resume makes start skip repos whose job store record shows they
+	// already finished this exact action, so an interrupted multi-repo run
+	// can be continued with `--resume` instead of starting over.
+	//
+	// Resume granularity is per-repo, not per-step: a repo that started but
+	// didn't finish is re-enqueued and re-run from its first step. This is a
+	// deliberate scope decision, not an oversight — ActionRepoStatus has no
+	// per-step checkpoint field, and introducing one would mean a step
+	// runner that can safely resume mid-step (skipping already-applied
+	// changes), which doesn't exist yet. Revisit if repos with many/slow
+	// steps make restarting from step 1 too expensive in practice.
+	resume   bool
+	jobStore *actionExecutionJobStore
+
+	// logRingBufferSize bounds the in-memory per-repo log buffer; see
+	// logRingBuffer. 0 uses defaultLogRingBufferSize.
+	logRingBufferSize int
+
+	// sink, if set, additionally receives a structured event for every
+	// status update so external tools can follow a run in real time.
+	sink actionLogSink
+
+	// scheduler orders and retries repo runs; tests can inject a
+	// deterministic one. Defaults to a priorityScheduler sized by the
+	// parallelism passed to newActionExecutor.
+	scheduler actionScheduler
+
+	// stepTimeout bounds a single repo's step execution, distinct from the
+	// overall run timeout, so one hung container can't stall the pool. 0
+	// disables it.
+	stepTimeout time.Duration
+
+	// cost estimates the relative expense of running a repo (e.g. its size
+	// or historical step duration from the cache), used by scheduler to
+	// start the most expensive repos first. Defaults to a constant cost,
+	// i.e. first-come-first-served.
+	cost func(ActionRepo) int64
+
 	onUpdate func(map[ActionRepo]ActionRepoStatus)
 }
 
@@ -25,23 +61,70 @@ type actionExecutor struct {
 	reposMu sync.Mutex
 	repos   map[ActionRepo]ActionRepoStatus
 
-	par           *parallel.Run
+	runErr        error
 	done          chan struct{}
 	doneEnqueuing chan struct{}
 
 	logger *actionLogger
+
+	logBuffersMu sync.Mutex
+	logBuffers   map[ActionRepo]*logRingBuffer
+}
+
+// logWriter returns the bounded ring buffer that collects repo's in-flight
+// step output, creating it on first use. x.do should write a repo's step
+// output here instead of directly to disk, so a large run's memory doesn't
+// grow without bound; updateRepoStatus flushes the buffer to status.LogFile
+// on a status update that carries an error, or on every status update when
+// keepLogs is set.
+func (x *actionExecutor) logWriter(repo ActionRepo) *logRingBuffer {
+	x.logBuffersMu.Lock()
+	defer x.logBuffersMu.Unlock()
+
+	if x.logBuffers == nil {
+		x.logBuffers = map[ActionRepo]*logRingBuffer{}
+	}
+	b, ok := x.logBuffers[repo]
+	if !ok {
+		b = newLogRingBuffer(x.opt.logRingBufferSize)
+		x.logBuffers[repo] = b
+	}
+	return b
+}
+
+// actionLogWriterKey is the context key start uses to thread a repo's log
+// ring buffer through to x.do, without changing x.do's signature.
+type actionLogWriterKey struct{}
+
+// withLogWriter returns a context carrying repo's log ring buffer. x.do (or
+// whatever ultimately streams a repo's step output) should look it up with
+// logWriterFromContext and write through it instead of directly to disk.
+func withLogWriter(ctx context.Context, w *logRingBuffer) context.Context {
+	return context.WithValue(ctx, actionLogWriterKey{}, w)
+}
+
+// logWriterFromContext returns the log ring buffer start attached to ctx via
+// withLogWriter, if any.
+func logWriterFromContext(ctx context.Context) (*logRingBuffer, bool) {
+	w, ok := ctx.Value(actionLogWriterKey{}).(*logRingBuffer)
+	return w, ok
 }
 
 func newActionExecutor(action Action, parallelism int, logger *actionLogger, opt actionExecutorOptions) *actionExecutor {
 	if opt.cache == nil {
 		opt.cache = actionExecutionNoOpCache{}
 	}
+	if opt.scheduler == nil {
+		opt.scheduler = newPriorityScheduler(parallelism, opt.stepTimeout)
+	}
+	if opt.cost == nil {
+		opt.cost = func(ActionRepo) int64 { return 0 }
+	}
 
 	return &actionExecutor{
 		action: action,
 		opt:    opt,
 		repos:  map[ActionRepo]ActionRepoStatus{},
-		par:    parallel.NewRun(parallelism),
 		logger: logger,
 
 		done:          make(chan struct{}),
@@ -55,7 +138,6 @@ func (x *actionExecutor) enqueueRepo(repo ActionRepo) {
 
 func (x *actionExecutor) updateRepoStatus(repo ActionRepo, status ActionRepoStatus) {
 	x.reposMu.Lock()
-	defer x.reposMu.Unlock()
 
 	// Perform delta update.
 	prev := x.repos[repo]
@@ -80,9 +162,87 @@ func (x *actionExecutor) updateRepoStatus(repo ActionRepo, status ActionRepoStat
 
 	x.repos[repo] = status
 
+	if x.opt.jobStore != nil {
+		// Record only updates in-memory state; the job store's background
+		// flusher batches the actual disk write, so this is cheap to call
+		// here while x.reposMu is held.
+		x.opt.jobStore.Record(repo, status, x.stepDigest())
+	}
+
 	if x.opt.onUpdate != nil {
 		x.opt.onUpdate(x.repos)
 	}
+
+	x.reposMu.Unlock()
+
+	// The log buffer flush (up to logRingBufferSize bytes to disk) and the
+	// sink emit (which can block on a slow or unresponsive consumer) are
+	// both real I/O, so they run after reposMu is released rather than
+	// while every other repo worker is waiting on it. This is the same
+	// off-the-hot-path treatment as the job store's flushLoop.
+	x.logBuffersMu.Lock()
+	buf := x.logBuffers[repo]
+	x.logBuffersMu.Unlock()
+
+	if buf != nil && status.LogFile != "" && (status.Err != nil || x.opt.keepLogs) {
+		if err := buf.FlushToFile(status.LogFile); err != nil {
+			x.logger.progress.Verbosef("failed to flush log buffer for %s: %s", repo.Name, err)
+		}
+	}
+
+	if x.opt.sink != nil {
+		event := statusToEvent(repo, status, buf)
+		if err := x.opt.sink.Emit(event); err != nil {
+			x.logger.progress.Verbosef("failed to emit log sink event for %s: %s", repo.Name, err)
+		}
+	}
+}
+
+// statusToEvent translates a status transition into the structured event
+// shape external tools consuming the log sink expect. buf is the repo's log
+// ring buffer, if one has been created yet; its current size becomes the
+// event's Bytes field.
+func statusToEvent(repo ActionRepo, status ActionRepoStatus, buf *logRingBuffer) actionLogEvent {
+	event := "enqueued"
+	switch {
+	case status.Err != nil:
+		event = "error"
+	case !status.FinishedAt.IsZero():
+		event = "finished"
+	case !status.StartedAt.IsZero():
+		event = "started"
+	}
+
+	bytes := 0
+	if buf != nil {
+		bytes = buf.Len()
+	}
+
+	return actionLogEvent{
+		Repo:  repo.Name,
+		Event: event,
+		Time:  time.Now(),
+		Bytes: bytes,
+	}
+}
+
+func (x *actionExecutor) stepDigest() string {
+	return actionDigest(x.action.Steps)
+}
+
+// Status returns a snapshot of the aggregate progress for this action run,
+// suitable for rendering a "N/total done" summary.
+func (x *actionExecutor) Status() (done, total int) {
+	x.reposMu.Lock()
+	defer x.reposMu.Unlock()
+
+	total = len(x.repos)
+	for _, status := range x.repos {
+		if !status.FinishedAt.IsZero() {
+			done++
+		}
+	}
+	return done, total
 }
 
 func (x *actionExecutor) allPatches() []PatchInput {
@@ -98,24 +258,9 @@ func (x *actionExecutor) allPatches() []PatchInput {
 }
 
 func (x *actionExecutor) start(ctx context.Context) {
-	if x.opt.onUpdate != nil {
-		go func() {
-			for {
-				select {
-				case <-x.done:
-					return
-				default:
-				}
-
-				x.reposMu.Lock()
-				x.opt.onUpdate(x.repos)
-				x.reposMu.Unlock()
-				time.Sleep(50 * time.Millisecond)
-			}
-		}()
-
-	}
-
+	// Status updates are pushed to onUpdate and the log sink directly from
+	// updateRepoStatus as they happen (see statusToEvent), rather than
+	// polled on an interval here.
 	x.reposMu.Lock()
 	allRepos := make([]ActionRepo, 0, len(x.repos))
 	for repo := range x.repos {
@@ -124,25 +269,44 @@ func (x *actionExecutor) start(ctx context.Context) {
 	x.reposMu.Unlock()
 	x.logger.progress.SetTotalSteps(int64(len(x.repos) * len(x.action.Steps)))
 
+	// --resume operates at repo granularity, not step granularity: a repo
+	// is skipped only if it fully finished against this exact action
+	// before. A repo that started but didn't finish is re-enqueued and
+	// re-run from its first step, the same as one that never ran.
+	digest := x.stepDigest()
+	pending := allRepos[:0]
 	for _, repo := range allRepos {
-		x.par.Acquire()
-		x.logger.progress.IncJobs()
-		go func(repo ActionRepo) {
-			defer x.par.Release()
-			defer x.logger.progress.DecJobs()
-			err := x.do(ctx, repo)
-			if err != nil {
-				x.par.Error(err)
+		if x.opt.resume && x.opt.jobStore != nil {
+			if state, ok := x.opt.jobStore.Lookup(repo); ok && state.StepDigest == digest && !state.Status.FinishedAt.IsZero() {
+				// Already completed this exact action against this repo in
+				// a prior run; restore its status without re-enqueuing it.
+				x.updateRepoStatus(repo, state.Status)
+				continue
 			}
-		}(repo)
+		}
+		pending = append(pending, repo)
 	}
+	allRepos = pending
+
+	go func() {
+		x.runErr = x.opt.scheduler.Run(ctx, allRepos, x.opt.cost, func(ctx context.Context, repo ActionRepo) error {
+			x.logger.progress.IncJobs()
+			defer x.logger.progress.DecJobs()
+			return x.do(withLogWriter(ctx, x.logWriter(repo)), repo)
+		})
+		close(x.done)
+	}()
 
 	close(x.doneEnqueuing)
 }
 
 func (x *actionExecutor) wait() error {
 	<-x.doneEnqueuing
-	err := x.par.Wait()
-	close(x.done)
-	return err
+	<-x.done
+
+	if x.opt.jobStore != nil {
+		x.opt.jobStore.Close()
+	}
+
+	return x.runErr
 }
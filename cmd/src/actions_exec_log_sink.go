@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultLogRingBufferSize bounds how much of a repo's step output is kept
+// in memory at once. Only the most recent bytes are retained; callers that
+// want the full log (e.g. on error, or when keepLogs is set) flush the
+// buffer to disk instead of holding it all in RAM for the whole run.
+const defaultLogRingBufferSize = 4 * 1024 * 1024 // 4MiB
+
+// logRingBuffer is a bounded, overwrite-oldest byte buffer used to hold a
+// repo's in-flight step output without letting a large multi-repo run's
+// memory grow unbounded. It implements io.Writer.
+type logRingBuffer struct {
+	mu   sync.Mutex
+	buf  []byte
+	size int
+	pos  int
+	full bool
+}
+
+// newLogRingBuffer returns a ring buffer that retains at most size bytes. A
+// size <= 0 falls back to defaultLogRingBufferSize.
+func newLogRingBuffer(size int) *logRingBuffer {
+	if size <= 0 {
+		size = defaultLogRingBufferSize
+	}
+	return &logRingBuffer{buf: make([]byte, size), size: size}
+}
+
+func (r *logRingBuffer) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := len(p)
+	for len(p) > 0 {
+		w := copy(r.buf[r.pos:], p)
+		r.pos += w
+		if r.pos == r.size {
+			r.pos = 0
+			r.full = true
+		}
+		p = p[w:]
+	}
+	return n, nil
+}
+
+// Len returns the number of bytes currently retained, without copying the
+// buffer contents the way Bytes does.
+func (r *logRingBuffer) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.full {
+		return r.size
+	}
+	return r.pos
+}
+
+// Bytes returns the retained contents in chronological order (oldest data
+// dropped first once the buffer has wrapped).
+func (r *logRingBuffer) Bytes() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		out := make([]byte, r.pos)
+		copy(out, r.buf[:r.pos])
+		return out
+	}
+
+	out := make([]byte, r.size)
+	copy(out, r.buf[r.pos:])
+	copy(out[r.size-r.pos:], r.buf[:r.pos])
+	return out
+}
+
+// FlushToFile writes the buffer's current contents to path, creating it if
+// necessary. Call this on error, or at the end of a run when keepLogs is
+// set, rather than writing every line to disk as it's produced.
+func (r *logRingBuffer) FlushToFile(path string) error {
+	return os.WriteFile(path, r.Bytes(), 0644)
+}
+
+// actionLogEvent is one structured progress event, suitable for consumption
+// by external tooling watching a multi-repo action run in real time. There's
+// no per-step field: ActionRepoStatus doesn't track which step a repo is on,
+// so there's nothing truthful to report there yet.
+type actionLogEvent struct {
+	Repo  string    `json:"repo"`
+	Event string    `json:"event"`
+	Time  time.Time `json:"ts"`
+	Bytes int       `json:"bytes"`
+}
+
+// actionLogSink receives structured events as an action run progresses. It
+// is the extension point for piping progress to external tools, in addition
+// to the interactive TTY/plain renderers.
+type actionLogSink interface {
+	Emit(event actionLogEvent) error
+	Close() error
+}
+
+// jsonFileSink appends newline-delimited JSON events to a file.
+type jsonFileSink struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// newJSONFileSink opens (creating/truncating) path for newline-delimited
+// JSON event output.
+func newJSONFileSink(path string) (*jsonFileSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonFileSink{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (s *jsonFileSink) Emit(event actionLogEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(event)
+}
+
+func (s *jsonFileSink) Close() error { return s.f.Close() }
+
+// jsonSocketSink writes newline-delimited JSON events to a Unix domain
+// socket, so a locally running tool can tail a run without polling a file.
+type jsonSocketSink struct {
+	mu   sync.Mutex
+	conn net.Conn
+	enc  *json.Encoder
+}
+
+// newJSONSocketSink dials the Unix socket at path and streams events to it.
+func newJSONSocketSink(path string) (*jsonSocketSink, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("dialing log sink socket %s: %w", path, err)
+	}
+	return &jsonSocketSink{conn: conn, enc: json.NewEncoder(conn)}, nil
+}
+
+func (s *jsonSocketSink) Emit(event actionLogEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(event)
+}
+
+func (s *jsonSocketSink) Close() error { return s.conn.Close() }
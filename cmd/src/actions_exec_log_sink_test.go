@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLogRingBufferBytesBeforeWrap(t *testing.T) {
+	r := newLogRingBuffer(8)
+	if _, err := r.Write([]byte("abc")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if got, want := r.Len(), 3; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	if got, want := r.Bytes(), []byte("abc"); !bytes.Equal(got, want) {
+		t.Fatalf("Bytes() = %q, want %q", got, want)
+	}
+}
+
+func TestLogRingBufferWrapKeepsChronologicalOrder(t *testing.T) {
+	r := newLogRingBuffer(4)
+	if _, err := r.Write([]byte("abcdef")); err != nil { // wraps once: "ef" overwrites "ab"
+		t.Fatalf("Write: %v", err)
+	}
+
+	if got, want := r.Len(), 4; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	if got, want := r.Bytes(), []byte("cdef"); !bytes.Equal(got, want) {
+		t.Fatalf("Bytes() = %q, want %q", got, want)
+	}
+}
+
+func TestLogRingBufferMultipleWritesAcrossWrap(t *testing.T) {
+	r := newLogRingBuffer(4)
+	writes := []string{"ab", "cd", "ef"}
+	for _, w := range writes {
+		if _, err := r.Write([]byte(w)); err != nil {
+			t.Fatalf("Write(%q): %v", w, err)
+		}
+	}
+
+	if got, want := r.Bytes(), []byte("cdef"); !bytes.Equal(got, want) {
+		t.Fatalf("Bytes() = %q, want %q", got, want)
+	}
+}
+
+func TestLogRingBufferDefaultsSizeWhenNonPositive(t *testing.T) {
+	r := newLogRingBuffer(0)
+	if got, want := r.size, defaultLogRingBufferSize; got != want {
+		t.Fatalf("size = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// jobStoreFlushInterval is how often the job store's background flusher
+// writes accumulated state to disk.
+const jobStoreFlushInterval = 500 * time.Millisecond
+
+// actionJobState is the durable record of one repo's progress through an
+// action run, keyed by repo so that `--resume` can tell which repos already
+// finished a previous invocation.
+//
+// There's no per-step granularity here: a repo that started but didn't
+// finish is re-run from the first step on resume, the same as a repo that
+// never ran at all. This is a deliberate, known scope limit of --resume, not
+// something left unfinished by accident: it requires ActionRepoStatus to
+// carry a per-step checkpoint, and a step runner able to resume mid-step
+// without reapplying already-applied changes, neither of which exists yet.
+// Acceptable for the common case of one-or-few-step actions; worth
+// revisiting if actions with many/slow steps make restarting from step 1
+// too expensive.
+type actionJobState struct {
+	Repo       ActionRepo       `json:"repo"`
+	Status     ActionRepoStatus `json:"status"`
+	StepDigest string           `json:"stepDigest"`
+}
+
+// actionExecutionJobStore persists ActionRepoStatus transitions to a JSON
+// file under the user's cache directory, keyed by action hash + repo, so
+// that a `--resume`'d run can skip repos that already finished. Writes are
+// batched by a background flusher (see flushLoop) rather than happening
+// inline in Record, since updateRepoStatus calls Record on every status
+// transition while holding actionExecutor's single repos mutex, and a
+// multi-repo run can produce far more transitions than it needs disk
+// writes.
+type actionExecutionJobStore struct {
+	path string
+
+	mu    sync.Mutex
+	state map[string]*actionJobState
+	dirty bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newActionExecutionJobStore opens (creating if necessary) the job store
+// for the action identified by actionID (a hash of the action definition).
+func newActionExecutionJobStore(actionID string) (*actionExecutionJobStore, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	dir = filepath.Join(dir, "sourcegraph", "src-cli", "actions")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &actionExecutionJobStore{
+		path:  filepath.Join(dir, actionID+".json"),
+		state: map[string]*actionJobState{},
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	go s.flushLoop()
+
+	return s, nil
+}
+
+func (s *actionExecutionJobStore) key(repo ActionRepo) string { return repo.Name }
+
+func (s *actionExecutionJobStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var entries []*actionJobState
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		s.state[s.key(e.Repo)] = e
+	}
+	return nil
+}
+
+// flushLoop periodically writes accumulated state to disk while the store
+// is dirty, and performs one last flush when Close is called.
+func (s *actionExecutionJobStore) flushLoop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(jobStoreFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			s.flushIfDirty()
+			return
+		case <-ticker.C:
+			s.flushIfDirty()
+		}
+	}
+}
+
+func (s *actionExecutionJobStore) flushIfDirty() {
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return
+	}
+	s.dirty = false
+
+	entries := make([]*actionJobState, 0, len(s.state))
+	for _, e := range s.state {
+		entries = append(entries, e)
+	}
+	s.mu.Unlock()
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(s.path, data, 0644)
+}
+
+// Record updates the in-memory status for repo along with the digest of the
+// steps it was run against, overwriting any previous record for that repo.
+// The change reaches disk on the next flushLoop tick (or on Close), not
+// synchronously, so this is cheap enough to call from the hot status-update
+// path.
+func (s *actionExecutionJobStore) Record(repo ActionRepo, status ActionRepoStatus, stepDigest string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state[s.key(repo)] = &actionJobState{Repo: repo, Status: status, StepDigest: stepDigest}
+	s.dirty = true
+}
+
+// Lookup returns the previously recorded state for repo, if any.
+func (s *actionExecutionJobStore) Lookup(repo ActionRepo) (*actionJobState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.state[s.key(repo)]
+	return e, ok
+}
+
+// Close flushes any pending state to disk and stops the background
+// flusher. Callers should call this once a run has finished.
+func (s *actionExecutionJobStore) Close() error {
+	close(s.stop)
+	<-s.done
+	return nil
+}
+
+// actionDigest returns a short, stable digest of an action's steps, used to
+// detect whether a cached FinishedAt record is still valid (i.e. the action
+// definition hasn't changed since it was recorded).
+func actionDigest(steps interface{}) string {
+	data, _ := json.Marshal(steps)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// actionScheduler runs fn once per repo with bounded concurrency. It exists
+// as an interface, rather than a concrete worker pool, so tests can inject a
+// deterministic scheduler instead of racing real goroutines.
+type actionScheduler interface {
+	// Run executes fn for each repo, ordering and retrying as the
+	// implementation sees fit, and returns the first error that retrying
+	// did not resolve (if any): a non-retryable error, or a retryable one
+	// once its retries are exhausted. cost estimates the relative expense of running
+	// fn for a repo (e.g. repo size, historical step duration); higher-cost
+	// repos should be started first to reduce tail latency.
+	Run(ctx context.Context, repos []ActionRepo, cost func(ActionRepo) int64, fn func(context.Context, ActionRepo) error) error
+}
+
+// defaultMaxRetries bounds how many times a single repo's step is retried
+// after a transient error before it's treated as a hard failure.
+const defaultMaxRetries = 3
+
+// defaultRetryBackoff is the base delay for exponential backoff between
+// retries; attempt N waits roughly defaultRetryBackoff * 2^(N-1).
+const defaultRetryBackoff = 2 * time.Second
+
+// priorityScheduler is a worker pool of fixed size that starts the
+// highest-cost repos first (so a few large repos don't end up queued behind
+// a long tail of small ones) and retries transient errors with exponential
+// backoff. Each job additionally gets its own stepTimeout, distinct from any
+// overall run timeout, so a single hung container can't stall the pool.
+type priorityScheduler struct {
+	parallelism int
+	maxRetries  int
+	backoff     time.Duration
+	stepTimeout time.Duration
+}
+
+// newPriorityScheduler returns a priorityScheduler with defaultMaxRetries,
+// defaultRetryBackoff, and the given parallelism and per-step timeout (0
+// disables the per-step timeout).
+func newPriorityScheduler(parallelism int, stepTimeout time.Duration) *priorityScheduler {
+	return &priorityScheduler{
+		parallelism: parallelism,
+		maxRetries:  defaultMaxRetries,
+		backoff:     defaultRetryBackoff,
+		stepTimeout: stepTimeout,
+	}
+}
+
+func (s *priorityScheduler) Run(ctx context.Context, repos []ActionRepo, cost func(ActionRepo) int64, fn func(context.Context, ActionRepo) error) error {
+	ordered := make([]ActionRepo, len(repos))
+	copy(ordered, repos)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return cost(ordered[i]) > cost(ordered[j])
+	})
+
+	jobs := make(chan ActionRepo)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	parallelism := s.parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range jobs {
+				if err := s.runWithRetry(ctx, repo, fn); err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+
+	for _, repo := range ordered {
+		select {
+		case jobs <- repo:
+		case <-ctx.Done():
+			close(jobs)
+			wg.Wait()
+			return ctx.Err()
+		}
+	}
+	close(jobs)
+
+	wg.Wait()
+	return firstErr
+}
+
+func (s *priorityScheduler) runWithRetry(ctx context.Context, repo ActionRepo, fn func(context.Context, ActionRepo) error) error {
+	var lastErr error
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := s.backoff * time.Duration(1<<uint(attempt-1))
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		jobCtx := ctx
+		var cancel context.CancelFunc
+		if s.stepTimeout > 0 {
+			jobCtx, cancel = context.WithTimeout(ctx, s.stepTimeout)
+		}
+
+		lastErr = fn(jobCtx, repo)
+		if cancel != nil {
+			cancel()
+		}
+
+		if lastErr == nil || !isTransientError(lastErr) {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+// isTransientError reports whether err looks like a transient infrastructure
+// failure (network hiccup, Docker pull failure) worth retrying, as opposed
+// to a deterministic failure (bad step, repo-specific error) that retrying
+// won't fix.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, substr := range []string{
+		"connection reset",
+		"connection refused",
+		"i/o timeout",
+		"eof",
+		"timeout",
+		"temporary failure",
+		"pull access denied",
+		"toomanyrequests",
+	} {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPrioritySchedulerOrdersByCostDescending(t *testing.T) {
+	repos := []ActionRepo{{Name: "low"}, {Name: "high"}, {Name: "mid"}}
+	cost := func(r ActionRepo) int64 {
+		switch r.Name {
+		case "high":
+			return 3
+		case "mid":
+			return 2
+		default:
+			return 1
+		}
+	}
+
+	s := newPriorityScheduler(1, 0) // parallelism 1 makes start order deterministic.
+
+	var mu sync.Mutex
+	var started []string
+
+	err := s.Run(context.Background(), repos, cost, func(ctx context.Context, r ActionRepo) error {
+		mu.Lock()
+		started = append(started, r.Name)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"high", "mid", "low"}
+	if len(started) != len(want) {
+		t.Fatalf("started = %v, want %v", started, want)
+	}
+	for i := range want {
+		if started[i] != want[i] {
+			t.Fatalf("started = %v, want %v", started, want)
+		}
+	}
+}
+
+func TestPrioritySchedulerRetriesTransientErrors(t *testing.T) {
+	s := newPriorityScheduler(1, 0)
+	s.backoff = time.Millisecond
+
+	var attempts int
+	err := s.Run(context.Background(), []ActionRepo{{Name: "flaky"}}, nil, func(ctx context.Context, r ActionRepo) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("connection reset by peer")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestPrioritySchedulerDoesNotRetryPermanentErrors(t *testing.T) {
+	s := newPriorityScheduler(1, 0)
+	s.backoff = time.Millisecond
+
+	var attempts int
+	wantErr := errors.New("step exited with status 1")
+	err := s.Run(context.Background(), []ActionRepo{{Name: "broken"}}, nil, func(ctx context.Context, r ActionRepo) error {
+		attempts++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Run() error = %v, want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestPrioritySchedulerStopsRetryingAfterMaxRetries(t *testing.T) {
+	s := newPriorityScheduler(1, 0)
+	s.backoff = time.Millisecond
+	s.maxRetries = 2
+
+	var attempts int
+	err := s.Run(context.Background(), []ActionRepo{{Name: "flaky"}}, nil, func(ctx context.Context, r ActionRepo) error {
+		attempts++
+		return errors.New("i/o timeout")
+	})
+	if err == nil {
+		t.Fatal("Run() error = nil, want non-nil after exhausting retries")
+	}
+	if want := s.maxRetries + 1; attempts != want {
+		t.Fatalf("attempts = %d, want %d", attempts, want)
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("connection refused"), true},
+		{errors.New("TooManyRequests"), true},
+		{errors.New("step exited with status 1"), false},
+	}
+	for _, c := range cases {
+		if got := isTransientError(c.err); got != c.want {
+			t.Fatalf("isTransientError(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
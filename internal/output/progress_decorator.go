@@ -0,0 +1,207 @@
+package output
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// DecoratorState is the progress snapshot a Decorator renders against. Both
+// ProgressBar and StatusBar can produce one, which lets the same decorators
+// be attached to either.
+type DecoratorState struct {
+	Value, Max float64
+}
+
+// Decorator renders supplementary text to the right of a ProgressBar or
+// StatusBar, such as ETA, throughput, or elapsed time. It is evaluated on
+// every draw and must render within Width() columns so that decorators
+// line up across bars, mirroring mpb's decorator model.
+type Decorator interface {
+	// Decorate returns the text to render for the given progress state.
+	Decorate(s DecoratorState) string
+
+	// Width returns the fixed column width this decorator reserves,
+	// including any padding.
+	Width() int
+}
+
+// decoratorObserver is implemented by decorators whose rendering depends on
+// samples taken only when a bar's value actually changes (ETA, throughput),
+// as opposed to every draw. A TTY redraw happens far more often than that
+// (e.g. every 100ms on the spinner tick), so observing in Decorate would
+// feed a stream of zero-delta samples between real updates and decay the
+// smoothed rate to zero regardless of true throughput. Callers that drive a
+// value forward (SetValue) notify decorators through this interface instead.
+type decoratorObserver interface {
+	observeValue(value float64, now time.Time)
+}
+
+// notifyDecoratorsObserved calls observeValue on every decorator in
+// decorators that implements decoratorObserver. Callers should invoke this
+// from SetValue, once per genuine value change, not from a redraw path.
+func notifyDecoratorsObserved(decorators []Decorator, value float64, now time.Time) {
+	for _, d := range decorators {
+		if o, ok := d.(decoratorObserver); ok {
+			o.observeValue(value, now)
+		}
+	}
+}
+
+// decoratorGap is the single space rendered between decorators and between
+// the bar and the first decorator.
+const decoratorGap = 1
+
+// decoratorsWidth returns the total width reserved for decorators, including
+// the gap rendered before each of them.
+func decoratorsWidth(decorators []Decorator) int {
+	width := 0
+	for _, d := range decorators {
+		width += decoratorGap + d.Width()
+	}
+	return width
+}
+
+// renderDecorators renders decorators left-to-right, each padded or
+// truncated to its own fixed width so columns stay aligned across bars.
+func renderDecorators(s DecoratorState, decorators []Decorator) string {
+	out := ""
+	for _, d := range decorators {
+		out += " " + runewidth.FillLeft(runewidth.Truncate(d.Decorate(s), d.Width(), ""), d.Width())
+	}
+	return out
+}
+
+// ewma tracks an exponential moving average of the rate of change (value per
+// second) observed between successive ProgressBar.SetValue calls. Smoothing
+// over the last few samples keeps speed/ETA decorators from jumping around
+// when steps complete in bursts.
+type ewma struct {
+	alpha float64
+	rate  float64
+
+	lastValue float64
+	lastTime  time.Time
+	started   bool
+}
+
+// newEWMA returns an ewma smoothed over approximately the given number of
+// samples.
+func newEWMA(samples int) *ewma {
+	if samples < 1 {
+		samples = 1
+	}
+	return &ewma{alpha: 2 / (float64(samples) + 1)}
+}
+
+func (e *ewma) observe(value float64, now time.Time) {
+	if !e.started {
+		e.lastValue, e.lastTime, e.started = value, now, true
+		return
+	}
+
+	dt := now.Sub(e.lastTime).Seconds()
+	if dt <= 0 {
+		return
+	}
+
+	instant := (value - e.lastValue) / dt
+	e.rate = e.alpha*instant + (1-e.alpha)*e.rate
+
+	e.lastValue, e.lastTime = value, now
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// DecoratorETA renders the estimated time remaining until a ProgressBar
+// reaches its Max, based on an EWMA of recent progress.
+type DecoratorETA struct {
+	ewma *ewma
+}
+
+// NewDecoratorETA returns a DecoratorETA whose rate is smoothed over the
+// last samples SetValue calls.
+func NewDecoratorETA(samples int) *DecoratorETA {
+	return &DecoratorETA{ewma: newEWMA(samples)}
+}
+
+func (d *DecoratorETA) Width() int { return 6 }
+
+func (d *DecoratorETA) observeValue(value float64, now time.Time) { d.ewma.observe(value, now) }
+
+func (d *DecoratorETA) Decorate(s DecoratorState) string {
+	if d.ewma.rate <= 0 || s.Value >= s.Max {
+		return "--:--"
+	}
+
+	remaining := (s.Max - s.Value) / d.ewma.rate
+	return formatDuration(time.Duration(remaining * float64(time.Second)))
+}
+
+// DecoratorSpeed renders an EWMA-smoothed throughput in unit/s.
+type DecoratorSpeed struct {
+	ewma *ewma
+	unit string
+}
+
+// NewDecoratorSpeed returns a DecoratorSpeed whose rate is smoothed over the
+// last samples SetValue calls and rendered using unit (e.g. "req", "KiB").
+func NewDecoratorSpeed(samples int, unit string) *DecoratorSpeed {
+	return &DecoratorSpeed{ewma: newEWMA(samples), unit: unit}
+}
+
+func (d *DecoratorSpeed) Width() int { return 10 }
+
+func (d *DecoratorSpeed) observeValue(value float64, now time.Time) { d.ewma.observe(value, now) }
+
+func (d *DecoratorSpeed) Decorate(s DecoratorState) string {
+	return fmt.Sprintf("%.1f %s/s", d.ewma.rate, d.unit)
+}
+
+// DecoratorElapsed renders the wall-clock time since the decorator was
+// created, independent of the bar's value.
+type DecoratorElapsed struct {
+	start time.Time
+}
+
+func NewDecoratorElapsed() *DecoratorElapsed {
+	return &DecoratorElapsed{start: time.Now()}
+}
+
+func (d *DecoratorElapsed) Width() int { return 5 }
+
+func (d *DecoratorElapsed) Decorate(s DecoratorState) string {
+	return formatDuration(time.Since(d.start))
+}
+
+// DecoratorPercentage renders Value/Max as a percentage.
+type DecoratorPercentage struct{}
+
+func NewDecoratorPercentage() *DecoratorPercentage { return &DecoratorPercentage{} }
+
+func (d *DecoratorPercentage) Width() int { return 4 }
+
+func (d *DecoratorPercentage) Decorate(s DecoratorState) string {
+	if s.Max <= 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%3.0f%%", (s.Value/s.Max)*100)
+}
+
+// DecoratorCountersKiB renders Value/Max as "done/total KiB".
+type DecoratorCountersKiB struct{}
+
+func NewDecoratorCountersKiB() *DecoratorCountersKiB { return &DecoratorCountersKiB{} }
+
+func (d *DecoratorCountersKiB) Width() int { return 16 }
+
+func (d *DecoratorCountersKiB) Decorate(s DecoratorState) string {
+	return fmt.Sprintf("%.1f/%.1fKiB", s.Value/1024, s.Max/1024)
+}
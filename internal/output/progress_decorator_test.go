@@ -0,0 +1,86 @@
+package output
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEWMAObserve(t *testing.T) {
+	e := newEWMA(1) // alpha = 1, i.e. no smoothing, for a deterministic rate.
+	start := time.Now()
+
+	e.observe(0, start) // first sample only seeds lastValue/lastTime.
+	if e.rate != 0 {
+		t.Fatalf("rate after first observe = %v, want 0", e.rate)
+	}
+
+	e.observe(10, start.Add(time.Second))
+	if e.rate != 10 {
+		t.Fatalf("rate after second observe = %v, want 10", e.rate)
+	}
+}
+
+func TestEWMAObserveIgnoresNonPositiveDelta(t *testing.T) {
+	e := newEWMA(1)
+	now := time.Now()
+
+	e.observe(0, now)
+	e.observe(10, now) // same timestamp, dt == 0, must not divide by zero.
+	if e.rate != 0 {
+		t.Fatalf("rate after zero-dt observe = %v, want 0", e.rate)
+	}
+}
+
+func TestDecoratorETADoesNotObserveOnDecorate(t *testing.T) {
+	d := NewDecoratorETA(1)
+	now := time.Now()
+
+	d.observeValue(0, now)
+	d.observeValue(10, now.Add(time.Second))
+
+	// Calling Decorate repeatedly (as a redraw loop would) must not change
+	// the smoothed rate; only observeValue (called from SetValue) may.
+	before := d.ewma.rate
+	_ = d.Decorate(DecoratorState{Value: 10, Max: 100})
+	_ = d.Decorate(DecoratorState{Value: 10, Max: 100})
+	if d.ewma.rate != before {
+		t.Fatalf("ewma.rate changed from Decorate alone: before=%v after=%v", before, d.ewma.rate)
+	}
+}
+
+func TestDecoratorETA(t *testing.T) {
+	d := NewDecoratorETA(1)
+	now := time.Now()
+
+	d.observeValue(0, now)
+	d.observeValue(10, now.Add(time.Second)) // rate = 10/s
+
+	got := d.Decorate(DecoratorState{Value: 10, Max: 110})
+	if want := "00:10"; got != want {
+		t.Fatalf("Decorate() = %q, want %q", got, want)
+	}
+
+	if got := d.Decorate(DecoratorState{Value: 10, Max: 10}); got != "--:--" {
+		t.Fatalf("Decorate() at Max = %q, want --:--", got)
+	}
+}
+
+func TestDecoratorPercentage(t *testing.T) {
+	d := NewDecoratorPercentage()
+
+	if got, want := d.Decorate(DecoratorState{Value: 5, Max: 10}), " 50%"; got != want {
+		t.Fatalf("Decorate() = %q, want %q", got, want)
+	}
+	if got, want := d.Decorate(DecoratorState{Value: 5, Max: 0}), "0%"; got != want {
+		t.Fatalf("Decorate() with Max=0 = %q, want %q", got, want)
+	}
+}
+
+func TestDecoratorCountersKiB(t *testing.T) {
+	d := NewDecoratorCountersKiB()
+
+	got := d.Decorate(DecoratorState{Value: 1024, Max: 2048})
+	if want := "1.0/2.0KiB"; got != want {
+		t.Fatalf("Decorate() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package output
+
+// ProgressWithStatusBars is the behavior shared by the TTY and plain
+// progress renderers, so callers such as actionLogger.progress can depend on
+// one interface regardless of which implementation they were handed.
+type ProgressWithStatusBars interface {
+	Close()
+	Destroy()
+	Complete()
+
+	IncJobs()
+	DecJobs()
+	SetTotalSteps(n int64)
+
+	SetLabel(i int, label string)
+	SetValue(i int, v float64)
+	SetBarDecorators(i int, decorators ...Decorator)
+
+	StatusBarResetf(i int, label, format string, args ...interface{})
+	StatusBarUpdatef(i int, format string, args ...interface{})
+	StatusBarCompletef(i int, format string, args ...interface{})
+	SetStatusBarDecorators(i int, decorators ...Decorator)
+
+	Verbose(s string)
+	Verbosef(format string, args ...interface{})
+	VerboseLine(line FancyLine)
+	Write(s string)
+	Writef(format string, args ...interface{})
+	WriteLine(line FancyLine)
+}
+
+// newProgressWithStatusBars picks the TTY renderer when o is attached to an
+// interactive terminal, and the append-only plain renderer otherwise (piped
+// to a file, running in CI, ...), so progress output stays useful either
+// way.
+func newProgressWithStatusBars(bars []*ProgressBar, statusBars []*StatusBar, o *Output, opts *ProgressOpts) ProgressWithStatusBars {
+	if o.caps.isatty {
+		return newProgressWithStatusBarsTTY(bars, statusBars, o, opts)
+	}
+	return newProgressWithStatusBarsPlain(bars, statusBars, o, opts)
+}
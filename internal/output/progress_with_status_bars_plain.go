@@ -0,0 +1,266 @@
+package output
+
+import (
+	"fmt"
+	"time"
+)
+
+// plainProgressThreshold is the minimum advancement, as a fraction of a
+// bar's Max, required before a line is emitted for it. SetValue always uses
+// this constant; there is no option that overrides it.
+const plainProgressThreshold = 0.05
+
+// plainHeartbeatInterval is how often a heartbeat line is emitted when no
+// other line (progress, reset, completion) has been printed for at least
+// that long, so that long-running, quiet steps don't look hung in a CI log.
+const plainHeartbeatInterval = 30 * time.Second
+
+// newProgressWithStatusBarsPlain returns a progress renderer for non-TTY
+// output (piped to a file, running in CI, ...). Unlike
+// progressWithStatusBarsTTY it never moves the cursor; it instead emits one
+// append-only line per meaningful event, which reads sensibly in a plain
+// log file.
+func newProgressWithStatusBarsPlain(bars []*ProgressBar, statusBars []*StatusBar, o *Output, opts *ProgressOpts) *progressWithStatusBarsPlain {
+	p := &progressWithStatusBarsPlain{
+		bars:                bars,
+		statusBars:          statusBars,
+		o:                   o,
+		lastPct:             make([]float64, len(bars)),
+		lastBeat:            time.Now(),
+		barDecorators:       map[*ProgressBar][]Decorator{},
+		statusBarDecorators: map[*StatusBar][]Decorator{},
+	}
+
+	if opts != nil {
+		p.opts = *opts
+	} else {
+		p.opts = defaultProgressTTYOpts
+	}
+
+	p.heartbeatDone = make(chan struct{})
+	go p.heartbeatLoop()
+
+	return p
+}
+
+type progressWithStatusBarsPlain struct {
+	bars       []*ProgressBar
+	statusBars []*StatusBar
+	o          *Output
+	opts       ProgressOpts
+
+	jobs       int64
+	totalSteps int64
+
+	lastPct  []float64
+	lastBeat time.Time
+
+	barDecorators       map[*ProgressBar][]Decorator
+	statusBarDecorators map[*StatusBar][]Decorator
+
+	heartbeatDone chan struct{}
+}
+
+func (p *progressWithStatusBarsPlain) heartbeatLoop() {
+	ticker := time.NewTicker(plainHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.heartbeatDone:
+			return
+		case <-ticker.C:
+			p.o.lock.Lock()
+			if time.Since(p.lastBeat) >= plainHeartbeatInterval {
+				p.printLine("heartbeat", "", DecoratorState{}, nil)
+			}
+			p.o.lock.Unlock()
+		}
+	}
+}
+
+// printLine emits a single append-only line summarizing current progress,
+// with a stable "[done/total pct%] event label" prefix so CI log viewers
+// can grep for it. label identifies the bar (if any) that triggered event;
+// decorators, rendered against state, are appended after the label since
+// the plain renderer has no fixed-width columns to align them into. Pass
+// nil decorators for events with no associated bar (e.g. the heartbeat).
+func (p *progressWithStatusBarsPlain) printLine(event, label string, state DecoratorState, decorators []Decorator) {
+	done := 0
+	pct := 0.0
+	for _, bar := range p.bars {
+		if bar.Value >= bar.Max {
+			done++
+		}
+	}
+	if len(p.bars) > 0 {
+		total := 0.0
+		value := 0.0
+		for _, bar := range p.bars {
+			total += bar.Max
+			value += bar.Value
+		}
+		if total > 0 {
+			pct = (value / total) * 100
+		}
+	}
+
+	decoratorsText := ""
+	for _, d := range decorators {
+		decoratorsText += " " + d.Decorate(state)
+	}
+
+	fmt.Fprintf(p.o.w, "[%d/%d %.0f%%] %s %s%s\n", done, len(p.bars), pct, event, label, decoratorsText)
+	p.lastBeat = time.Now()
+}
+
+func (p *progressWithStatusBarsPlain) Close()   { p.Destroy() }
+func (p *progressWithStatusBarsPlain) Destroy() { close(p.heartbeatDone) }
+
+func (p *progressWithStatusBarsPlain) IncJobs() { p.jobs++ }
+func (p *progressWithStatusBarsPlain) DecJobs() { p.jobs-- }
+
+func (p *progressWithStatusBarsPlain) SetTotalSteps(n int64) { p.totalSteps = n }
+
+func (p *progressWithStatusBarsPlain) Complete() {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	var last *ProgressBar
+	for _, bar := range p.bars {
+		bar.Value = bar.Max
+		last = bar
+	}
+
+	label := ""
+	var state DecoratorState
+	var decorators []Decorator
+	if last != nil {
+		label = last.Label
+		state = DecoratorState{Value: last.Value, Max: last.Max}
+		decorators = p.barDecorators[last]
+	}
+	p.printLine("complete", label, state, decorators)
+}
+
+func (p *progressWithStatusBarsPlain) SetLabel(i int, label string) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	p.bars[i].Label = label
+}
+
+func (p *progressWithStatusBarsPlain) SetValue(i int, v float64) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	p.bars[i].Value = v
+	notifyDecoratorsObserved(p.barDecorators[p.bars[i]], v, time.Now())
+
+	if p.bars[i].Max <= 0 {
+		return
+	}
+
+	pct := v / p.bars[i].Max
+	if pct-p.lastPct[i] >= plainProgressThreshold || v >= p.bars[i].Max {
+		p.lastPct[i] = pct
+		state := DecoratorState{Value: p.bars[i].Value, Max: p.bars[i].Max}
+		p.printLine("progress", p.bars[i].Label, state, p.barDecorators[p.bars[i]])
+	}
+}
+
+// SetBarDecorators sets the decorators considered when this bar's line is
+// emitted. The plain renderer has no fixed-width columns to align, so
+// decorators are appended after the label rather than right-justified.
+func (p *progressWithStatusBarsPlain) SetBarDecorators(i int, decorators ...Decorator) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	p.barDecorators[p.bars[i]] = decorators
+}
+
+// SetStatusBarDecorators sets the decorators considered when status bar i's
+// line is emitted.
+func (p *progressWithStatusBarsPlain) SetStatusBarDecorators(i int, decorators ...Decorator) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	if p.statusBars[i] != nil {
+		p.statusBarDecorators[p.statusBars[i]] = decorators
+	}
+}
+
+func (p *progressWithStatusBarsPlain) StatusBarResetf(i int, label, format string, args ...interface{}) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	var decorators []Decorator
+	if p.statusBars[i] != nil {
+		p.statusBars[i].Resetf(label, format, args...)
+		decorators = p.statusBarDecorators[p.statusBars[i]]
+	}
+	p.printLine("reset", label, DecoratorState{}, decorators)
+}
+
+func (p *progressWithStatusBarsPlain) StatusBarUpdatef(i int, format string, args ...interface{}) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	if p.statusBars[i] != nil {
+		p.statusBars[i].Updatef(format, args...)
+	}
+}
+
+func (p *progressWithStatusBarsPlain) StatusBarCompletef(i int, format string, args ...interface{}) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	label := ""
+	var decorators []Decorator
+	if p.statusBars[i] != nil {
+		p.statusBars[i].Completef(format, args...)
+		label = p.statusBars[i].label
+		decorators = p.statusBarDecorators[p.statusBars[i]]
+	}
+	p.printLine("status-complete", label, DecoratorState{}, decorators)
+}
+
+func (p *progressWithStatusBarsPlain) Verbose(s string) {
+	if p.o.opts.Verbose {
+		p.Write(s)
+	}
+}
+
+func (p *progressWithStatusBarsPlain) Verbosef(format string, args ...interface{}) {
+	if p.o.opts.Verbose {
+		p.Writef(format, args...)
+	}
+}
+
+func (p *progressWithStatusBarsPlain) VerboseLine(line FancyLine) {
+	if p.o.opts.Verbose {
+		p.WriteLine(line)
+	}
+}
+
+func (p *progressWithStatusBarsPlain) Write(s string) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	fmt.Fprintln(p.o.w, s)
+}
+
+func (p *progressWithStatusBarsPlain) Writef(format string, args ...interface{}) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	fmt.Fprintf(p.o.w, format, p.o.caps.formatArgs(args)...)
+	fmt.Fprint(p.o.w, "\n")
+}
+
+func (p *progressWithStatusBarsPlain) WriteLine(line FancyLine) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	line.write(p.o.w, p.o.caps)
+}
@@ -2,6 +2,7 @@ package output
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mattn/go-runewidth"
@@ -16,7 +17,9 @@ func newProgressWithStatusBarsTTY(bars []*ProgressBar, statusBars []*StatusBar,
 			pendingEmoji: spinnerStrings[0],
 			spinner:      newSpinner(100 * time.Millisecond),
 		},
-		statusBars: statusBars,
+		statusBars:          statusBars,
+		barDecorators:       map[*ProgressBar][]Decorator{},
+		statusBarDecorators: map[*StatusBar][]Decorator{},
 	}
 
 	if opts != nil {
@@ -56,6 +59,36 @@ type progressWithStatusBarsTTY struct {
 
 	statusBars          []*StatusBar
 	statusBarLabelWidth int
+
+	// barDecorators and statusBarDecorators hold the decorators (ETA,
+	// throughput, elapsed time, ...) rendered on the right of each bar,
+	// keyed by the bar itself rather than by index so they survive bars
+	// being reordered by callers that hold onto a *ProgressBar/*StatusBar.
+	barDecorators       map[*ProgressBar][]Decorator
+	statusBarDecorators map[*StatusBar][]Decorator
+}
+
+// SetBarDecorators sets the decorators rendered to the right of bar i,
+// replacing any previously set for that bar.
+func (p *progressWithStatusBarsTTY) SetBarDecorators(i int, decorators ...Decorator) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	p.barDecorators[p.bars[i]] = decorators
+	p.drawInSitu()
+}
+
+// SetStatusBarDecorators sets the decorators rendered to the right of status
+// bar i, replacing any previously set for that bar.
+func (p *progressWithStatusBarsTTY) SetStatusBarDecorators(i int, decorators ...Decorator) {
+	p.o.lock.Lock()
+	defer p.o.lock.Unlock()
+
+	if p.statusBars[i] != nil {
+		p.statusBarDecorators[p.statusBars[i]] = decorators
+	}
+	p.determineStatusBarLabelWidth()
+	p.drawInSitu()
 }
 
 func (p *progressWithStatusBarsTTY) Close() { p.Destroy() }
@@ -103,6 +136,7 @@ func (p *progressWithStatusBarsTTY) SetValue(i int, v float64) {
 	defer p.o.lock.Unlock()
 
 	p.bars[i].Value = v
+	notifyDecoratorsObserved(p.barDecorators[p.bars[i]], v, time.Now())
 	p.drawInSitu()
 }
 
@@ -164,15 +198,19 @@ func (p *progressWithStatusBarsTTY) drawInSitu() {
 
 func (p *progressWithStatusBarsTTY) determineStatusBarLabelWidth() {
 	p.statusBarLabelWidth = 0
+	maxDecoratorsWidth := 0
 	for _, bar := range p.statusBars {
 		labelWidth := runewidth.StringWidth(bar.label)
 		if labelWidth > p.statusBarLabelWidth {
 			p.statusBarLabelWidth = labelWidth
 		}
+		if w := decoratorsWidth(p.statusBarDecorators[bar]); w > maxDecoratorsWidth {
+			maxDecoratorsWidth = w
+		}
 	}
 
 	statusBarEmojiWidth := p.emojiWidth + 1 // statusBars have one more space at start
-	if maxWidth := p.o.caps.Width/2 - statusBarEmojiWidth; (p.statusBarLabelWidth + 2) > maxWidth {
+	if maxWidth := p.o.caps.Width/2 - statusBarEmojiWidth - maxDecoratorsWidth; (p.statusBarLabelWidth + 2) > maxWidth {
 		p.statusBarLabelWidth = maxWidth - 2
 	}
 }
@@ -185,14 +223,53 @@ func (p *progressWithStatusBarsTTY) writeStatusBar(i int, statusBar *StatusBar)
 		style = StyleSuccess
 	}
 
+	decorators := p.statusBarDecorators[statusBar]
+	decoratorsText := renderDecorators(DecoratorState{}, decorators)
+
 	labelFillWidth := p.statusBarLabelWidth + 2
 	label := runewidth.FillRight(runewidth.Truncate(statusBar.label, p.statusBarLabelWidth, "..."), labelFillWidth)
 
-	textMaxLength := p.o.caps.Width - (p.emojiWidth + 1) - labelFillWidth
+	textMaxLength := p.o.caps.Width - (p.emojiWidth + 1) - labelFillWidth - decoratorsWidth(decorators)
 	text := runewidth.Truncate(fmt.Sprintf(statusBar.format, p.o.caps.formatArgs(statusBar.args)...), textMaxLength, "...")
 
 	p.o.clearCurrentLine()
-	fmt.Fprint(p.o.w, style, " ", runewidth.FillLeft(emoji, p.emojiWidth+1), " ", label, text, StyleReset, "\n")
+	fmt.Fprint(p.o.w, style, " ", runewidth.FillLeft(emoji, p.emojiWidth+1), " ", label, text, decoratorsText, StyleReset, "\n")
+}
+
+// writeBar draws a ProgressBar, shadowing progressTTY's embedded writeBar so
+// that decorators set via SetBarDecorators are rendered to the right of the
+// bar, with their width reserved the same way writeStatusBar reserves space
+// for statusBarDecorators.
+func (p *progressWithStatusBarsTTY) writeBar(bar *ProgressBar) {
+	emoji := p.pendingEmoji
+	style := StylePending
+	if bar.Value >= bar.Max {
+		emoji = EmojiSuccess
+		style = StyleSuccess
+	}
+
+	decorators := p.barDecorators[bar]
+	decoratorsText := renderDecorators(DecoratorState{Value: bar.Value, Max: bar.Max}, decorators)
+
+	labelFillWidth := bar.labelWidth + 2
+	label := runewidth.FillRight(runewidth.Truncate(bar.Label, bar.labelWidth, "..."), labelFillWidth)
+
+	barWidth := p.o.caps.Width - (p.emojiWidth + 1) - labelFillWidth - decoratorsWidth(decorators)
+	if barWidth < 0 {
+		barWidth = 0
+	}
+
+	filled := 0
+	if bar.Max > 0 {
+		filled = int(float64(barWidth) * (bar.Value / bar.Max))
+		if filled > barWidth {
+			filled = barWidth
+		}
+	}
+	barText := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+
+	p.o.clearCurrentLine()
+	fmt.Fprint(p.o.w, style, " ", runewidth.FillLeft(emoji, p.emojiWidth+1), " ", label, barText, decoratorsText, StyleReset, "\n")
 }
 
 func (p *progressWithStatusBarsTTY) Verbose(s string) {